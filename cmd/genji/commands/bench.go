@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/genjidb/genji"
+	"github.com/urfave/cli/v2"
+)
+
+// NewBenchCommand returns the `genji bench` subcommand, which repeatedly runs
+// a single SQL statement against a chosen engine and reports timing
+// statistics, useful for spotting query performance regressions.
+func NewBenchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "bench",
+		Usage:     "Run a SQL statement repeatedly and report timing statistics",
+		UsageText: "genji bench [options] query",
+		Description: `
+The bench command runs the given SQL statement repeatedly against a database
+and prints one JSON (or CSV) line per sample with the average time spent per
+query in that sample.
+
+$ genji bench -e bolt --db my.db -n 1000 -s 100 'SELECT * FROM foo WHERE a = 1'
+
+Pre-statements, for example to create a table and seed some data, can be run
+once before the benchmark starts using --init, which may be repeated:
+
+$ genji bench --init 'CREATE TABLE foo' --init 'INSERT INTO foo (a) VALUES (1)' 'SELECT * FROM foo'
+
+By default the statement is parsed and planned once and only re-executed on
+each iteration. Pass --reparse to re-parse and re-plan the statement on
+every iteration instead, which is useful to measure parsing/planning
+overhead in isolation from execution.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "engine",
+				Aliases: []string{"e"},
+				Usage:   "name of the engine to use, options are 'bolt', 'badger' or 'memory'",
+				Value:   "memory",
+			},
+			&cli.StringFlag{
+				Name:  "db",
+				Usage: "path of the database file",
+			},
+			&cli.StringSliceFlag{
+				Name:  "init",
+				Usage: "statement to run once before the benchmark starts, can be repeated",
+			},
+			&cli.IntFlag{
+				Name:    "number",
+				Aliases: []string{"n"},
+				Usage:   "total number of iterations to run",
+				Value:   100,
+			},
+			&cli.IntFlag{
+				Name:    "sample",
+				Aliases: []string{"s"},
+				Usage:   "number of iterations per reported sample",
+				Value:   10,
+			},
+			&cli.BoolFlag{
+				Name:  "reparse",
+				Usage: "re-parse and re-plan the statement on every iteration instead of preparing it once",
+			},
+			&cli.BoolFlag{
+				Name:  "csv",
+				Usage: "print samples as CSV instead of JSON lines",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			query := c.Args().First()
+			if query == "" {
+				return fmt.Errorf("missing query argument: %s", c.Command.UsageText)
+			}
+
+			return runBenchCommand(c.Context, benchOptions{
+				engine:  c.String("engine"),
+				dbPath:  c.String("db"),
+				init:    c.StringSlice("init"),
+				query:   query,
+				number:  c.Int("number"),
+				sample:  c.Int("sample"),
+				reparse: c.Bool("reparse"),
+				csv:     c.Bool("csv"),
+			})
+		},
+	}
+}
+
+type benchOptions struct {
+	engine  string
+	dbPath  string
+	init    []string
+	query   string
+	number  int
+	sample  int
+	reparse bool
+	csv     bool
+}
+
+type benchSample struct {
+	TotalQueries int    `json:"totalQueries"`
+	SampleSpeed  string `json:"sampleSpeed"`
+}
+
+func runBenchCommand(ctx context.Context, opt benchOptions) error {
+	db, err := OpenDB(opt.engine, opt.dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, stmt := range opt.init {
+		if err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to run init statement %q: %w", stmt, err)
+		}
+	}
+
+	var stmt *genji.Statement
+	if !opt.reparse {
+		s, err := db.Prepare(opt.query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare query: %w", err)
+		}
+		stmt = s
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+
+	total := 0
+	sampleStart := time.Now()
+	sampleCount := 0
+
+	flush := func() {
+		if sampleCount == 0 {
+			return
+		}
+		avg := time.Since(sampleStart) / time.Duration(sampleCount)
+		printSample(w, opt.csv, benchSample{
+			TotalQueries: total,
+			SampleSpeed:  avg.String(),
+		})
+		sampleCount = 0
+		sampleStart = time.Now()
+	}
+
+	for i := 0; i < opt.number; i++ {
+		select {
+		case <-sigCh:
+			flush()
+			return nil
+		default:
+		}
+
+		var err error
+		if opt.reparse {
+			err = db.ExecContext(ctx, opt.query)
+		} else {
+			err = stmt.Run(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("query failed on iteration %d: %w", i, err)
+		}
+
+		total++
+		sampleCount++
+
+		if sampleCount == opt.sample {
+			flush()
+		}
+	}
+
+	flush()
+
+	return nil
+}
+
+func printSample(w *csv.Writer, asCSV bool, s benchSample) {
+	if asCSV {
+		_ = w.Write([]string{fmt.Sprint(s.TotalQueries), s.SampleSpeed})
+		w.Flush()
+		return
+	}
+
+	_ = json.NewEncoder(os.Stdout).Encode(s)
+}
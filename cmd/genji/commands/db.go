@@ -0,0 +1,44 @@
+// Package commands holds genji CLI subcommands that are small enough to not
+// warrant their own package, but numerous enough that main.go stays readable.
+package commands
+
+import (
+	"fmt"
+
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/engine"
+	"github.com/genjidb/genji/engine/badger"
+	"github.com/genjidb/genji/engine/bolt"
+	"github.com/genjidb/genji/engine/memory"
+)
+
+// OpenDB opens a *genji.DB using the given engine name ("bolt", "badger" or
+// "memory") and, for disk-backed engines, the given path.
+func OpenDB(engineName, path string) (*genji.DB, error) {
+	var ng engine.Engine
+	var err error
+
+	switch engineName {
+	case "bolt":
+		if path == "" {
+			return nil, fmt.Errorf("--db is required when using the bolt engine")
+		}
+		ng, err = bolt.NewEngine(path, 0660, nil)
+	case "badger":
+		ng, err = badger.NewEngine(badger.Options{Path: path})
+	case "memory":
+		ng = memory.NewEngine()
+	default:
+		return nil, fmt.Errorf("unsupported engine %q, only 'bolt', 'badger' or 'memory' can be used", engineName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s engine: %w", engineName, err)
+	}
+
+	db, err := genji.New(ng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return db, nil
+}
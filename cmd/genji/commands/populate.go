@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// NewPopulateCommand returns the `genji populate` subcommand, which inserts
+// synthetic rows into an existing table, handy for preparing a dataset to
+// run `genji bench` against or to explore manually.
+func NewPopulateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "populate",
+		Usage:     "Insert synthetic rows into an existing table",
+		UsageText: "genji populate [options]",
+		Description: `
+The populate command reads the target table's schema and inserts randomly
+generated rows into it, in batched transactions.
+
+$ genji populate --db my.db -t foo -n 100000
+
+A column-specific generator can be forced through --template, pointing to a
+JSON file mapping column names to either a generator name or a generator
+configuration:
+
+$ cat schema.json
+{"email": "email", "age": {"type": "int", "min": 18, "max": 99}}
+$ genji populate --db my.db -t foo -n 1000 --template schema.json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "engine",
+				Aliases: []string{"e"},
+				Usage:   "name of the engine to use, options are 'bolt', 'badger' or 'memory'",
+				Value:   "bolt",
+			},
+			&cli.StringFlag{
+				Name:  "db",
+				Usage: "path of the database file",
+			},
+			&cli.StringFlag{
+				Name:     "table",
+				Aliases:  []string{"t"},
+				Usage:    "name of the table to populate, it must already exist",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:    "number",
+				Aliases: []string{"n"},
+				Usage:   "number of rows to insert",
+				Value:   1000,
+			},
+			&cli.IntFlag{
+				Name:  "batch",
+				Usage: "number of rows per transaction",
+				Value: 500,
+			},
+			&cli.Int64Flag{
+				Name:  "seed",
+				Usage: "seed for the random generator, for reproducible data sets",
+			},
+			&cli.StringFlag{
+				Name:  "template",
+				Usage: "path to a JSON file overriding the generator used for specific columns",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			seed := c.Int64("seed")
+			if !c.IsSet("seed") {
+				seed = time.Now().UnixNano()
+			}
+
+			overrides, err := loadTemplate(c.String("template"))
+			if err != nil {
+				return err
+			}
+
+			return runPopulateCommand(c.Context, populateOptions{
+				engine:    c.String("engine"),
+				dbPath:    c.String("db"),
+				table:     c.String("table"),
+				number:    c.Int("number"),
+				batch:     c.Int("batch"),
+				seed:      seed,
+				overrides: overrides,
+			})
+		},
+	}
+}
+
+type populateOptions struct {
+	engine    string
+	dbPath    string
+	table     string
+	number    int
+	batch     int
+	seed      int64
+	overrides map[string]generatorSpec
+}
+
+func loadTemplate(path string) (map[string]generatorSpec, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open template %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(f).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+
+	overrides := make(map[string]generatorSpec, len(raw))
+	for column, msg := range raw {
+		var spec generatorSpec
+
+		var name string
+		if err := json.Unmarshal(msg, &name); err == nil {
+			spec.Type = name
+		} else if err := json.Unmarshal(msg, &spec); err != nil {
+			return nil, fmt.Errorf("invalid generator for column %q: %w", column, err)
+		}
+
+		overrides[column] = spec
+	}
+
+	return overrides, nil
+}
+
+func runPopulateCommand(ctx context.Context, opt populateOptions) error {
+	db, err := OpenDB(opt.engine, opt.dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	info, err := loadTableInfo(ctx, db, opt.table)
+	if err != nil {
+		return err
+	}
+
+	rng := rand.New(rand.NewSource(opt.seed))
+	gens := make([]fieldGenerator, len(info.columns))
+	for i, col := range info.columns {
+		if col.primaryKey {
+			start, err := maxPrimaryKey(ctx, db, opt.table, col.name)
+			if err != nil {
+				return err
+			}
+			gens[i] = &counterGenerator{n: start}
+			continue
+		}
+		gens[i] = newFieldGenerator(col, opt.overrides[col.name], rng)
+	}
+
+	tx, err := db.Begin(true)
+	if err != nil {
+		return err
+	}
+	inBatch := 0
+
+	for i := 0; i < opt.number; i++ {
+		args := make([]interface{}, len(gens))
+		for j, gen := range gens {
+			args[j] = gen.next()
+		}
+
+		if err := tx.ExecContext(ctx, info.insertStmt, args...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to insert row %d: %w", i, err)
+		}
+
+		inBatch++
+		if inBatch >= opt.batch {
+			if err := tx.Commit(); err != nil {
+				return err
+			}
+			tx, err = db.Begin(true)
+			if err != nil {
+				return err
+			}
+			inBatch = 0
+		}
+	}
+
+	return tx.Commit()
+}
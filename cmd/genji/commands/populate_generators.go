@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/genjidb/genji"
+)
+
+// column describes a single field of the table being populated, as declared
+// in its CREATE TABLE statement.
+type column struct {
+	name       string
+	sqlType    string
+	primaryKey bool
+}
+
+// tableInfo bundles what's needed to generate and insert rows for a table.
+type tableInfo struct {
+	columns    []column
+	insertStmt string
+}
+
+// loadTableInfo reads the CREATE TABLE statement of table from
+// __genji_tables and turns it into a list of columns.
+func loadTableInfo(ctx context.Context, db *genji.DB, table string) (*tableInfo, error) {
+	stream, err := db.QueryContext(ctx, "SELECT sql FROM __genji_tables WHERE name = ?", table)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var stmt string
+	found := false
+	err = stream.Iterate(func(d genjiDocument) error {
+		v, err := d.GetByField("sql")
+		if err != nil {
+			return err
+		}
+		stmt = fmt.Sprint(v)
+		found = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("table %q does not exist", table)
+	}
+
+	columns, err := parseCreateTable(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+		placeholders[i] = "?"
+	}
+
+	return &tableInfo{
+		columns:    columns,
+		insertStmt: fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(names, ", "), strings.Join(placeholders, ", ")),
+	}, nil
+}
+
+// parseCreateTable extracts column name/type/constraint triples from a
+// "CREATE TABLE name (col type [constraints], ...)" statement.
+func parseCreateTable(stmt string) ([]column, error) {
+	open := strings.IndexByte(stmt, '(')
+	close := strings.LastIndexByte(stmt, ')')
+	if open < 0 || close < 0 || close < open {
+		return nil, fmt.Errorf("failed to parse schema from %q", stmt)
+	}
+
+	var columns []column
+	for _, part := range strings.Split(stmt[open+1:close], ",") {
+		fields := strings.Fields(strings.TrimSpace(part))
+		if len(fields) == 0 {
+			continue
+		}
+
+		col := column{name: fields[0], sqlType: "text"}
+		if len(fields) > 1 {
+			col.sqlType = strings.ToLower(fields[1])
+		}
+		if strings.Contains(strings.ToUpper(part), "PRIMARY KEY") {
+			col.primaryKey = true
+		}
+
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
+
+// generatorSpec configures a generator for a single column, either by name
+// ("email") or with bounds ({"type": "int", "min": 18, "max": 99}).
+type generatorSpec struct {
+	Type string  `json:"type"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+// fieldGenerator produces one value per call to next().
+type fieldGenerator interface {
+	next() interface{}
+}
+
+// newFieldGenerator builds the generator for a non-primary-key column;
+// primary keys are handled by the caller via maxPrimaryKey and
+// counterGenerator, since they need to know the table's current max value.
+func newFieldGenerator(col column, spec generatorSpec, rng *rand.Rand) fieldGenerator {
+	typ := spec.Type
+	if typ == "" {
+		typ = col.sqlType
+	}
+
+	switch typ {
+	case "email":
+		return &emailGenerator{rng: rng}
+	case "text", "varchar":
+		return &textGenerator{rng: rng}
+	case "bool", "boolean":
+		return &boolGenerator{rng: rng}
+	case "float", "double", "real":
+		return &floatGenerator{rng: rng, min: spec.Min, max: orDefault(spec.Max, 1000)}
+	case "document", "array":
+		return &nestedGenerator{rng: rng}
+	default:
+		return &intGenerator{rng: rng, min: int64(spec.Min), max: int64(orDefault(spec.Max, 1000))}
+	}
+}
+
+func orDefault(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// maxPrimaryKey returns the current highest value of column pk in table, or
+// 0 if the table is empty. populate seeds its counterGenerator from it so
+// that running populate against a table that already has rows, or running
+// it more than once, doesn't immediately collide on a duplicate primary key.
+func maxPrimaryKey(ctx context.Context, db *genji.DB, table, pk string) (int64, error) {
+	stream, err := db.QueryContext(ctx, fmt.Sprintf("SELECT MAX(%s) AS m FROM %s", pk, table))
+	if err != nil {
+		return 0, err
+	}
+	defer stream.Close()
+
+	var max int64
+	err = stream.Iterate(func(d genjiDocument) error {
+		v, err := d.GetByField("m")
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			return nil
+		}
+		_, err = fmt.Sscanf(fmt.Sprint(v), "%d", &max)
+		return err
+	})
+
+	return max, err
+}
+
+type counterGenerator struct{ n int64 }
+
+func (g *counterGenerator) next() interface{} {
+	g.n++
+	return g.n
+}
+
+type intGenerator struct {
+	rng      *rand.Rand
+	min, max int64
+}
+
+func (g *intGenerator) next() interface{} {
+	if g.max <= g.min {
+		return g.min
+	}
+	return g.min + g.rng.Int63n(g.max-g.min)
+}
+
+type floatGenerator struct {
+	rng      *rand.Rand
+	min, max float64
+}
+
+func (g *floatGenerator) next() interface{} {
+	return g.min + g.rng.Float64()*(g.max-g.min)
+}
+
+type boolGenerator struct{ rng *rand.Rand }
+
+func (g *boolGenerator) next() interface{} {
+	return g.rng.Intn(2) == 1
+}
+
+var firstNames = []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank", "Grace", "Heidi"}
+var lastNames = []string{"Smith", "Johnson", "Lee", "Brown", "Garcia", "Martin", "Davis", "Clark"}
+
+type textGenerator struct{ rng *rand.Rand }
+
+func (g *textGenerator) next() interface{} {
+	return firstNames[g.rng.Intn(len(firstNames))] + " " + lastNames[g.rng.Intn(len(lastNames))]
+}
+
+type emailGenerator struct{ rng *rand.Rand }
+
+func (g *emailGenerator) next() interface{} {
+	name := strings.ToLower(firstNames[g.rng.Intn(len(firstNames))])
+	domain := []string{"example.com", "mail.test", "example.org"}[g.rng.Intn(3)]
+	return fmt.Sprintf("%s%d@%s", name, g.rng.Intn(10000), domain)
+}
+
+type nestedGenerator struct{ rng *rand.Rand }
+
+func (g *nestedGenerator) next() interface{} {
+	return map[string]interface{}{
+		"a": g.rng.Intn(100),
+		"b": firstNames[g.rng.Intn(len(firstNames))],
+	}
+}
@@ -0,0 +1,6 @@
+package commands
+
+import "github.com/genjidb/genji/document"
+
+// genjiDocument is a local alias to keep call sites in this package short.
+type genjiDocument = document.Document
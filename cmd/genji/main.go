@@ -7,6 +7,7 @@ import (
 	"runtime/debug"
 	"strings"
 
+	"github.com/genjidb/genji/cmd/genji/commands"
 	"github.com/genjidb/genji/cmd/genji/shell"
 	"github.com/urfave/cli/v2"
 )
@@ -144,6 +145,8 @@ $ genji restore -e badger -t foo dump.sql my.db
 				return runRestoreCommand(c.Context, file, engine, table, args[1])
 			},
 		},
+		commands.NewBenchCommand(),
+		commands.NewPopulateCommand(),
 		{
 			Name:  "version",
 			Usage: "Shows Genji and Genji CLI version",
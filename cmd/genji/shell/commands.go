@@ -0,0 +1,60 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// runDotCommand handles a line starting with ".", returning true if the
+// shell should exit.
+func (sh *shell) runDotCommand(ctx context.Context, line string) (bool, error) {
+	fields := strings.Fields(line)
+	name := fields[0]
+	args := fields[1:]
+
+	switch name {
+	case ".exit":
+		return true, nil
+	case ".schema":
+		return false, sh.runSchema(ctx, args)
+	case ".save":
+		return false, sh.runSave(ctx, args)
+	case ".import":
+		return false, sh.runImport(ctx, args)
+	default:
+		return false, fmt.Errorf("unknown command %q", name)
+	}
+}
+
+func (sh *shell) runSchema(ctx context.Context, args []string) error {
+	query := "SELECT sql FROM __genji_tables"
+	var params []interface{}
+	if len(args) > 0 {
+		query += " WHERE name = ?"
+		params = append(params, args[0])
+	}
+
+	stream, err := sh.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	return stream.Iterate(func(d genjiDocument) error {
+		sql, err := d.GetByField("sql")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(sh.out, sql)
+		return nil
+	})
+}
+
+func (sh *shell) runSave(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: .save path/to/file.db")
+	}
+
+	return sh.db.Dump(ctx, args[0])
+}
@@ -0,0 +1,524 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultImportBatchSize is how many rows .import commits per transaction,
+// and how many CSV rows it samples to infer column types, unless overridden
+// with --batch.
+const defaultImportBatchSize = 1000
+
+// runImport implements the ".import TYPE FILE TABLE" dot-command, a
+// sqlite-style bulk loader for CSV and newline-delimited/array JSON files.
+func (sh *shell) runImport(ctx context.Context, args []string) error {
+	noHeader := false
+	batchSize := defaultImportBatchSize
+	var positional []string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--no-header":
+			noHeader = true
+		case "--batch":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--batch requires a value")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("--batch must be a positive integer, got %q", args[i])
+			}
+			batchSize = n
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	if len(positional) != 3 {
+		return fmt.Errorf("usage: .import [--no-header] [--batch N] csv|json FILE TABLE")
+	}
+
+	typ, path, table := positional[0], positional[1], positional[2]
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	switch typ {
+	case "csv":
+		return sh.importCSV(ctx, f, table, noHeader, batchSize)
+	case "json":
+		return sh.importJSON(ctx, f, table, batchSize)
+	default:
+		return fmt.Errorf("unsupported import type %q, expected 'csv' or 'json'", typ)
+	}
+}
+
+// importCSV streams rows from r into table, inferring a schema from the
+// first batchSize rows when the table doesn't already exist.
+func (sh *shell) importCSV(ctx context.Context, r io.Reader, table string, noHeader bool, batchSize int) error {
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := header
+	var firstRow []string
+	if noHeader {
+		columns = make([]string, len(header))
+		for i := range header {
+			columns[i] = fmt.Sprintf("c%d", i+1)
+		}
+		firstRow = header
+	}
+
+	exists, err := sh.tableExists(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	sample, types, err := sampleCSVTypes(cr, columns, firstRow, batchSize)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := sh.createTableFromTypes(ctx, table, columns, types); err != nil {
+			return err
+		}
+	} else if err := sh.checkColumnCompatibility(ctx, table, columns, types); err != nil {
+		return err
+	}
+
+	return sh.insertCSVRows(ctx, table, columns, sample, cr, batchSize)
+}
+
+// sampleCSVTypes reads up to batchSize rows to guess an
+// integer/float/bool/text type per column. Since csv.Reader has no way to
+// unread what it consumes, the sampled rows are returned alongside the
+// inferred types so the caller can insert them instead of silently dropping
+// them.
+func sampleCSVTypes(cr *csv.Reader, columns []string, firstRow []string, batchSize int) ([][]string, []string, error) {
+	types := make([]string, len(columns))
+	for i := range types {
+		types[i] = "integer"
+	}
+
+	var sample [][]string
+	if firstRow != nil {
+		sample = append(sample, firstRow)
+	}
+
+	for len(sample) < batchSize {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		sample = append(sample, row)
+	}
+
+	for _, row := range sample {
+		for i, v := range row {
+			if i >= len(types) {
+				break
+			}
+			types[i] = widenType(types[i], v)
+		}
+	}
+
+	return sample, types, nil
+}
+
+func widenType(current, value string) string {
+	if current == "text" {
+		return "text"
+	}
+
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if current == "integer" || current == "float" {
+			return current
+		}
+		return "text"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		if current == "integer" || current == "float" {
+			return "float"
+		}
+		return "text"
+	}
+	if _, err := strconv.ParseBool(value); err == nil && (current == "integer" || current == "boolean") {
+		return "boolean"
+	}
+
+	return "text"
+}
+
+func (sh *shell) createTableFromTypes(ctx context.Context, table string, columns, types []string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (", table)
+	for i, col := range columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s %s", col, sqlType(types[i]))
+	}
+	b.WriteString(")")
+
+	return sh.db.ExecContext(ctx, b.String())
+}
+
+func sqlType(inferred string) string {
+	switch inferred {
+	case "boolean":
+		return "BOOL"
+	case "float":
+		return "DOUBLE"
+	case "text":
+		return "TEXT"
+	default:
+		return "INTEGER"
+	}
+}
+
+func (sh *shell) tableExists(ctx context.Context, table string) (bool, error) {
+	stream, err := sh.db.QueryContext(ctx, "SELECT name FROM __genji_tables WHERE name = ?", table)
+	if err != nil {
+		return false, err
+	}
+	defer stream.Close()
+
+	found := false
+	err = stream.Iterate(func(d genjiDocument) error {
+		found = true
+		return nil
+	})
+
+	return found, err
+}
+
+// checkColumnCompatibility reports the first CSV column, in order, that
+// either doesn't exist in table or whose inferred type (types[i]) isn't
+// compatible with the column's declared type.
+func (sh *shell) checkColumnCompatibility(ctx context.Context, table string, columns, types []string) error {
+	declared, err := sh.tableColumns(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]shellColumn, len(declared))
+	for _, c := range declared {
+		byName[c.name] = c
+	}
+
+	for i, c := range columns {
+		col, ok := byName[c]
+		if !ok {
+			return fmt.Errorf("column %q does not exist in table %q", c, table)
+		}
+		if !typeCompatible(types[i], col.sqlType) {
+			return fmt.Errorf("column %q is declared as %s in table %q, incompatible with inferred type %s", c, col.sqlType, table, types[i])
+		}
+	}
+
+	return nil
+}
+
+// typeCompatible reports whether a value inferred as inferredType from CSV
+// text can be inserted into a column declared with sqlType.
+func typeCompatible(inferredType, sqlType string) bool {
+	declared := strings.ToUpper(sqlType)
+
+	switch inferredType {
+	case "boolean":
+		return declared == "BOOL" || declared == "BOOLEAN"
+	case "integer":
+		switch declared {
+		case "INTEGER", "INT", "DOUBLE", "FLOAT", "REAL", "TEXT":
+			return true
+		}
+		return false
+	case "float":
+		switch declared {
+		case "DOUBLE", "FLOAT", "REAL", "TEXT":
+			return true
+		}
+		return false
+	case "text":
+		return declared == "TEXT"
+	default:
+		return false
+	}
+}
+
+// shellColumn is a column name/declared-type pair parsed out of a table's
+// CREATE TABLE statement.
+type shellColumn struct {
+	name    string
+	sqlType string
+}
+
+// tableColumns returns the columns declared in table's CREATE TABLE
+// statement, as recorded in __genji_tables.
+func (sh *shell) tableColumns(ctx context.Context, table string) ([]shellColumn, error) {
+	stream, err := sh.db.QueryContext(ctx, "SELECT sql FROM __genji_tables WHERE name = ?", table)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var stmt string
+	found := false
+	err = stream.Iterate(func(d genjiDocument) error {
+		v, err := d.GetByField("sql")
+		if err != nil {
+			return err
+		}
+		stmt = fmt.Sprint(v)
+		found = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("table %q does not exist", table)
+	}
+
+	return parseColumns(stmt)
+}
+
+// parseColumns extracts column name/declared-type pairs from a
+// "CREATE TABLE name (col type [constraints], ...)" statement.
+func parseColumns(stmt string) ([]shellColumn, error) {
+	open := strings.IndexByte(stmt, '(')
+	close := strings.LastIndexByte(stmt, ')')
+	if open < 0 || close < 0 || close < open {
+		return nil, fmt.Errorf("failed to parse schema from %q", stmt)
+	}
+
+	var columns []shellColumn
+	for _, part := range strings.Split(stmt[open+1:close], ",") {
+		fields := strings.Fields(strings.TrimSpace(part))
+		if len(fields) == 0 {
+			continue
+		}
+
+		col := shellColumn{name: fields[0], sqlType: "TEXT"}
+		if len(fields) > 1 {
+			col.sqlType = strings.ToUpper(fields[1])
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
+
+// parseColumnNames extracts just the column names from a
+// "CREATE TABLE name (col type [constraints], ...)" statement.
+func parseColumnNames(stmt string) ([]string, error) {
+	columns, err := parseColumns(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+
+	return names, nil
+}
+
+// insertCSVRows inserts sample (the rows already consumed from cr while
+// sampling types) followed by the remainder of cr, in batchSize-sized
+// transactions.
+func (sh *shell) insertCSVRows(ctx context.Context, table string, columns []string, sample [][]string, cr *csv.Reader, batchSize int) error {
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), placeholders(len(columns)))
+
+	tx, err := sh.db.Begin(true)
+	if err != nil {
+		return err
+	}
+	inBatch := 0
+
+	flush := func() error {
+		if inBatch == 0 {
+			return nil
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		tx, err = sh.db.Begin(true)
+		if err != nil {
+			return err
+		}
+		inBatch = 0
+		return nil
+	}
+
+	insertRow := func(row []string) error {
+		args := make([]interface{}, len(row))
+		for i, v := range row {
+			args[i] = v
+		}
+
+		if err := tx.ExecContext(ctx, insert, args...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to insert row: %w", err)
+		}
+
+		inBatch++
+		if inBatch >= batchSize {
+			return flush()
+		}
+		return nil
+	}
+
+	for _, row := range sample {
+		if err := insertRow(row); err != nil {
+			return err
+		}
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := insertRow(row); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func placeholders(n int) string {
+	ph := make([]string, n)
+	for i := range ph {
+		ph[i] = "?"
+	}
+	return strings.Join(ph, ", ")
+}
+
+// importJSON accepts either a top-level JSON array of objects or a stream of
+// newline-delimited objects, inserting into table (which must already
+// exist, mirroring what `genji insert` expects from stdin) in batchSize-sized
+// transactions.
+func (sh *shell) importJSON(ctx context.Context, r io.Reader, table string, batchSize int) error {
+	br := bufio.NewReader(r)
+
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return err
+	}
+
+	tx, err := sh.db.Begin(true)
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s RECORDS ?", table)
+	inBatch := 0
+	lineNum := 0
+
+	flush := func() error {
+		if inBatch == 0 {
+			return nil
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		tx, err = sh.db.Begin(true)
+		if err != nil {
+			return err
+		}
+		inBatch = 0
+		return nil
+	}
+
+	insertDoc := func(raw json.RawMessage) error {
+		if err := tx.ExecContext(ctx, insert, raw); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to insert document at line %d: %w", lineNum, err)
+		}
+		inBatch++
+		if inBatch >= batchSize {
+			return flush()
+		}
+		return nil
+	}
+
+	dec := json.NewDecoder(br)
+
+	if first == '[' {
+		if _, err := dec.Token(); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("invalid JSON array at line %d: %w", lineNum, err)
+		}
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("invalid JSON document at line %d: %w", lineNum, err)
+			}
+			if err := insertDoc(raw); err != nil {
+				return err
+			}
+		}
+	} else {
+		for {
+			var raw json.RawMessage
+			err := dec.Decode(&raw)
+			if err == io.EOF {
+				break
+			}
+			lineNum++
+			if err != nil {
+				tx.Rollback()
+				return fmt.Errorf("invalid JSON document at line %d: %w", lineNum, err)
+			}
+			if err := insertDoc(raw); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if b[0] == ' ' || b[0] == '\t' || b[0] == '\n' || b[0] == '\r' {
+			if _, err := br.Discard(1); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		return b[0], nil
+	}
+}
@@ -0,0 +1,108 @@
+package shell
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWidenType(t *testing.T) {
+	tests := []struct {
+		current, value, want string
+	}{
+		{"integer", "42", "integer"},
+		{"integer", "3.14", "float"},
+		{"integer", "true", "boolean"},
+		{"integer", "hello", "text"},
+		{"float", "2", "float"},
+		{"text", "42", "text"},
+	}
+
+	for _, tt := range tests {
+		if got := widenType(tt.current, tt.value); got != tt.want {
+			t.Errorf("widenType(%q, %q) = %q, want %q", tt.current, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSqlType(t *testing.T) {
+	tests := map[string]string{
+		"boolean": "BOOL",
+		"float":   "DOUBLE",
+		"text":    "TEXT",
+		"integer": "INTEGER",
+	}
+
+	for inferred, want := range tests {
+		if got := sqlType(inferred); got != want {
+			t.Errorf("sqlType(%q) = %q, want %q", inferred, got, want)
+		}
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	if got := placeholders(3); got != "?, ?, ?" {
+		t.Errorf("placeholders(3) = %q, want %q", got, "?, ?, ?")
+	}
+	if got := placeholders(0); got != "" {
+		t.Errorf("placeholders(0) = %q, want empty string", got)
+	}
+}
+
+func TestParseColumnNames(t *testing.T) {
+	got, err := parseColumnNames("CREATE TABLE foo (a INTEGER PRIMARY KEY, b TEXT, c BOOL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseColumnNames = %v, want %v", got, want)
+	}
+}
+
+func TestParseColumnNamesInvalid(t *testing.T) {
+	if _, err := parseColumnNames("not a create table statement"); err == nil {
+		t.Fatal("expected an error for a statement without parentheses")
+	}
+}
+
+func TestSampleCSVTypesDoesNotDropRows(t *testing.T) {
+	cr := csv.NewReader(strings.NewReader("1,b\n2,c\n"))
+
+	sample, types, err := sampleCSVTypes(cr, []string{"a", "b"}, nil, defaultImportBatchSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSample := [][]string{{"1", "b"}, {"2", "c"}}
+	if !reflect.DeepEqual(sample, wantSample) {
+		t.Errorf("sample = %v, want %v", sample, wantSample)
+	}
+
+	wantTypes := []string{"integer", "text"}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Errorf("types = %v, want %v", types, wantTypes)
+	}
+
+	// The rows fed to type inference must still be available for insertion:
+	// cr itself should now be exhausted, since sampleCSVTypes consumed it.
+	if _, err := cr.Read(); err == nil {
+		t.Fatal("expected reader to be exhausted after sampling its only rows")
+	}
+}
+
+func TestSampleCSVTypesIncludesFirstRow(t *testing.T) {
+	cr := csv.NewReader(strings.NewReader("2,c\n"))
+
+	sample, _, err := sampleCSVTypes(cr, []string{"a", "b"}, []string{"1", "b"}, defaultImportBatchSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"1", "b"}, {"2", "c"}}
+	if !reflect.DeepEqual(sample, want) {
+		t.Errorf("sample = %v, want %v", sample, want)
+	}
+}
@@ -0,0 +1,93 @@
+// Package shell implements genji's interactive SQL shell, along with a set
+// of dot-commands (".exit", ".schema", ...) that control the shell itself
+// rather than the database.
+package shell
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/cmd/genji/commands"
+)
+
+// Options holds the shell configuration.
+type Options struct {
+	// Engine is the name of the engine to use: "bolt", "badger" or "memory".
+	Engine string
+	// DBPath is the path to the database file, empty for the memory engine.
+	DBPath string
+}
+
+// Run starts the interactive shell, reading statements and dot-commands from
+// stdin until EOF, ".exit" or an unrecoverable error.
+func Run(ctx context.Context, opt *Options) error {
+	db, err := commands.OpenDB(opt.Engine, opt.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	sh := &shell{
+		db:  db,
+		in:  bufio.NewReader(os.Stdin),
+		out: os.Stdout,
+	}
+
+	return sh.run(ctx)
+}
+
+type shell struct {
+	db  *genji.DB
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (sh *shell) run(ctx context.Context) error {
+	for {
+		fmt.Fprint(sh.out, "genji> ")
+
+		line, readErr := sh.in.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		// On EOF, ReadString still returns whatever partial line it read
+		// (the normal case for a final statement with no trailing newline,
+		// e.g. piped or heredoc input) — process it before exiting.
+		if exit := sh.runLine(ctx, line); exit {
+			return nil
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// runLine processes a single line of input, returning true if the shell
+// should exit.
+func (sh *shell) runLine(ctx context.Context, line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+
+	if strings.HasPrefix(line, ".") {
+		exit, err := sh.runDotCommand(ctx, line)
+		if err != nil {
+			fmt.Fprintf(sh.out, "error: %v\n", err)
+			return false
+		}
+		return exit
+	}
+
+	if err := sh.db.ExecContext(ctx, line); err != nil {
+		fmt.Fprintf(sh.out, "error: %v\n", err)
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package genji
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+var _ driver.Conn = (*conn)(nil)
+
+// conn is a database/sql connection wrapping a *DB. Several conns can share
+// the same *DB when handed out by a connector, so Close must not close db:
+// the shared *DB's lifecycle belongs to whoever owns the connector (its
+// finalizer, or an explicit top-level Close), not to any one pooled conn.
+type conn struct {
+	db *DB
+}
+
+func newConn(db *DB) *conn {
+	return &conn{db: db}
+}
+
+// Prepare implements driver.Conn.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &driverStmt{stmt: stmt}, nil
+}
+
+// Close implements driver.Conn. It intentionally does not close c.db: with
+// connection pooling, database/sql may close idle conns at any time while
+// other conns still share the same underlying *DB.
+func (c *conn) Close() error {
+	return nil
+}
+
+// Begin implements driver.Conn.
+func (c *conn) Begin() (driver.Tx, error) {
+	tx, err := c.db.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &driverTx{tx: tx}, nil
+}
+
+// driverStmt adapts a *Statement to driver.Stmt.
+type driverStmt struct {
+	stmt *Statement
+}
+
+func (s *driverStmt) Close() error  { return nil }
+func (s *driverStmt) NumInput() int { return -1 }
+
+func (s *driverStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.stmt.Run(context.Background()); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (s *driverStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+// driverTx adapts a *Tx to driver.Tx.
+type driverTx struct {
+	tx *Tx
+}
+
+func (t *driverTx) Commit() error   { return t.tx.Commit() }
+func (t *driverTx) Rollback() error { return t.tx.Rollback() }
@@ -0,0 +1,80 @@
+package planner
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/genjidb/genji/document"
+)
+
+// approxHintPattern matches the `/*+ approx */` optimizer hint anywhere in a
+// query's source text.
+var approxHintPattern = regexp.MustCompile(`(?i)/\*\+\s*approx\s*\*/`)
+
+// pragmaApproxDistinct is the name of the session pragma that turns on
+// approximate COUNT(DISTINCT ...) for every query in the session, without
+// requiring a per-query hint.
+const pragmaApproxDistinct = "approx_distinct"
+
+// Pragmas holds the current session's PRAGMA settings relevant to planning.
+// The planner consults it the same way it inspects a query's hints.
+type Pragmas map[string]string
+
+// ApproxDistinctEnabled reports whether `PRAGMA approx_distinct = on` has
+// been set for the session.
+func (p Pragmas) ApproxDistinctEnabled() bool {
+	return strings.EqualFold(p[pragmaApproxDistinct], "on")
+}
+
+// shouldUseApproxDistinct decides, for a given query and the session's
+// pragmas, whether COUNT(DISTINCT ...) should be planned against the
+// HyperLogLog estimator (documentHLLSet) instead of the exact set
+// (documentHashSet). It is true when the query carries an `/*+ approx */`
+// hint, or the approx_distinct pragma is on; the exact set remains the
+// default otherwise.
+func shouldUseApproxDistinct(query string, pragmas Pragmas) bool {
+	return pragmas.ApproxDistinctEnabled() || approxHintPattern.MatchString(query)
+}
+
+// distinctCounter is what a COUNT(DISTINCT expr) aggregator needs from its
+// underlying set, whether exact or estimated.
+type distinctCounter interface {
+	Add(d document.Document) error
+	Count() uint64
+}
+
+var (
+	_ distinctCounter = (*documentHashSet)(nil)
+	_ distinctCounter = (*documentHLLSet)(nil)
+)
+
+// Count implements distinctCounter for the exact set by counting its keys.
+func (s *documentHashSet) Count() uint64 {
+	return uint64(len(s.set))
+}
+
+// Add implements distinctCounter for the exact set, ignoring the "is this a
+// new document" bool that Filter reports since COUNT(DISTINCT ...) only
+// cares about how many unique keys were seen.
+func (s *documentHashSet) Add(d document.Document) error {
+	_, err := s.Filter(d)
+	return err
+}
+
+// NewDistinctCounter picks the distinctCounter a COUNT(DISTINCT expr)
+// aggregation should feed for the given query and session pragmas: the
+// approximate HyperLogLog estimator when the `/*+ approx */` hint or the
+// approx_distinct pragma is present, the exact set otherwise.
+//
+// This package does not contain the aggregation/optimizer rule that would
+// call it against a real query plan — there is no COUNT(DISTINCT ...)
+// expression, aggregator or optimizer rule anywhere in this tree yet for it
+// to plug into. Wiring this in is left to whoever adds that plumbing; until
+// then this function (and the sets behind it) is reachable only from tests.
+func NewDistinctCounter(query string, pragmas Pragmas) distinctCounter {
+	if shouldUseApproxDistinct(query, pragmas) {
+		return newDocumentHLLSet(nil, 0)
+	}
+
+	return newDocumentHashSet(nil)
+}
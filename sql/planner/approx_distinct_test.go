@@ -0,0 +1,52 @@
+package planner
+
+import "testing"
+
+func TestPragmasApproxDistinctEnabled(t *testing.T) {
+	tests := []struct {
+		pragmas Pragmas
+		want    bool
+	}{
+		{nil, false},
+		{Pragmas{}, false},
+		{Pragmas{"approx_distinct": "on"}, true},
+		{Pragmas{"approx_distinct": "ON"}, true},
+		{Pragmas{"approx_distinct": "off"}, false},
+		{Pragmas{"other": "on"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pragmas.ApproxDistinctEnabled(); got != tt.want {
+			t.Errorf("Pragmas(%v).ApproxDistinctEnabled() = %v, want %v", tt.pragmas, got, tt.want)
+		}
+	}
+}
+
+func TestShouldUseApproxDistinct(t *testing.T) {
+	tests := []struct {
+		query   string
+		pragmas Pragmas
+		want    bool
+	}{
+		{"SELECT COUNT(DISTINCT a) FROM t", nil, false},
+		{"SELECT /*+ approx */ COUNT(DISTINCT a) FROM t", nil, true},
+		{"SELECT /*+APPROX*/ COUNT(DISTINCT a) FROM t", nil, true},
+		{"SELECT COUNT(DISTINCT a) FROM t", Pragmas{"approx_distinct": "on"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := shouldUseApproxDistinct(tt.query, tt.pragmas); got != tt.want {
+			t.Errorf("shouldUseApproxDistinct(%q, %v) = %v, want %v", tt.query, tt.pragmas, got, tt.want)
+		}
+	}
+}
+
+func TestNewDistinctCounter(t *testing.T) {
+	if _, ok := NewDistinctCounter("SELECT COUNT(DISTINCT a) FROM t", nil).(*documentHashSet); !ok {
+		t.Fatal("expected the exact set by default")
+	}
+
+	if _, ok := NewDistinctCounter("SELECT /*+ approx */ COUNT(DISTINCT a) FROM t", nil).(*documentHLLSet); !ok {
+		t.Fatal("expected the HLL estimator when the approx hint is present")
+	}
+}
@@ -0,0 +1,158 @@
+package planner
+
+import (
+	"fmt"
+	"hash"
+	"hash/maphash"
+	"math"
+	"math/bits"
+
+	"github.com/genjidb/genji/document"
+)
+
+// hllPrecision is the number of bits of the hash used as a register index.
+// With p = 14 there are 2^14 = 16384 registers and a standard error of
+// about 1.04/sqrt(2^p) ≈ 0.81%.
+const hllPrecision = 14
+
+// documentHLLSet estimates the number of distinct documents seen using a
+// HyperLogLog sketch, trading the exact count documentHashSet provides for
+// O(2^p) memory regardless of the number of documents seen. It is meant for
+// COUNT(DISTINCT ...) aggregates where the exact set of distinct rows isn't
+// needed downstream. See NewDistinctCounter for the rule that picks between
+// this estimator and documentHashSet.
+type documentHLLSet struct {
+	hash      hash.Hash64
+	registers []uint8
+	p         uint
+}
+
+// newDocumentHLLSet creates a documentHLLSet with 2^p registers. p defaults
+// to hllPrecision when 0.
+func newDocumentHLLSet(hash hash.Hash64, p uint) *documentHLLSet {
+	if hash == nil {
+		hash = &maphash.Hash{}
+	}
+	if p == 0 {
+		p = hllPrecision
+	}
+
+	return &documentHLLSet{
+		hash:      hash,
+		registers: make([]uint8, 1<<p),
+		p:         p,
+	}
+}
+
+func (s *documentHLLSet) generateKey(d document.Document) (uint64, error) {
+	defer s.hash.Reset()
+
+	fields, err := document.Fields(d)
+	if err != nil {
+		return 0, err
+	}
+
+	enc := document.NewValueEncoder(s.hash)
+
+	for _, field := range fields {
+		value, err := d.GetByField(field)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := enc.Encode(value); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.hash.Sum64(), nil
+}
+
+// Add hashes d and updates the sketch, it never rejects a document.
+func (s *documentHLLSet) Add(d document.Document) error {
+	k, err := s.generateKey(d)
+	if err != nil {
+		return err
+	}
+
+	s.addHash(k)
+	return nil
+}
+
+func (s *documentHLLSet) addHash(h uint64) {
+	bucket := h & (uint64(len(s.registers)) - 1)
+	rest := h >> s.p
+
+	rank := uint8(1)
+	if rest != 0 {
+		rank = uint8(bits.LeadingZeros64(rest)-int(s.p)) + 1
+	} else {
+		rank = uint8(64 - s.p + 1)
+	}
+
+	if rank > s.registers[bucket] {
+		s.registers[bucket] = rank
+	}
+}
+
+// Count returns the estimated number of distinct documents added so far,
+// satisfying the distinctCounter interface that NewDistinctCounter selects
+// between this estimator and the exact documentHashSet.
+func (s *documentHLLSet) Count() uint64 {
+	return s.Estimate()
+}
+
+// Merge folds other into s, taking the max of each pair of registers. s and
+// other must have been created with the same precision, otherwise Merge
+// returns an error instead of merging.
+func (s *documentHLLSet) Merge(other *documentHLLSet) error {
+	if len(other.registers) != len(s.registers) {
+		return fmt.Errorf("cannot merge HyperLogLog sets with different precisions (%d vs %d registers)", len(s.registers), len(other.registers))
+	}
+
+	for i, r := range other.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+
+	return nil
+}
+
+// Estimate returns the estimated number of distinct documents added so far.
+func (s *documentHLLSet) Estimate() uint64 {
+	m := float64(len(s.registers))
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range s.registers {
+		sum += math.Pow(2, -float64(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := hllAlpha(m) * m * m / sum
+
+	// Small-range correction: fall back to linear counting when a
+	// significant fraction of registers are still empty.
+	if estimate <= 2.5*m && zeros > 0 {
+		return uint64(m * math.Log(m/float64(zeros)))
+	}
+
+	return uint64(estimate)
+}
+
+// hllAlpha returns the bias correction constant for m registers.
+func hllAlpha(m float64) float64 {
+	switch {
+	case m == 16:
+		return 0.673
+	case m == 32:
+		return 0.697
+	case m == 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/m)
+	}
+}
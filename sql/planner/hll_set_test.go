@@ -0,0 +1,71 @@
+package planner
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDocumentHLLSetEstimate(t *testing.T) {
+	const n = 100000
+
+	s := newDocumentHLLSet(nil, 14)
+	rng := rand.New(rand.NewSource(1))
+
+	seen := map[uint64]struct{}{}
+	for len(seen) < n {
+		seen[rng.Uint64()] = struct{}{}
+	}
+
+	for h := range seen {
+		s.addHash(h)
+	}
+
+	got := s.Count()
+	errRatio := math.Abs(float64(got)-float64(n)) / float64(n)
+	if errRatio > 0.05 {
+		t.Fatalf("estimate %d too far from actual %d (error ratio %.4f)", got, n, errRatio)
+	}
+}
+
+func TestDocumentHLLSetMerge(t *testing.T) {
+	a := newDocumentHLLSet(nil, 10)
+	b := newDocumentHLLSet(nil, 10)
+	rng := rand.New(rand.NewSource(2))
+
+	want := map[uint64]struct{}{}
+	for i := 0; i < 5000; i++ {
+		h := rng.Uint64()
+		want[h] = struct{}{}
+		if i%2 == 0 {
+			a.addHash(h)
+		} else {
+			b.addHash(h)
+		}
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+
+	errRatio := math.Abs(float64(a.Count())-float64(len(want))) / float64(len(want))
+	if errRatio > 0.1 {
+		t.Fatalf("merged estimate %d too far from actual %d (error ratio %.4f)", a.Count(), len(want), errRatio)
+	}
+}
+
+func TestDocumentHLLSetMergeMismatchedPrecision(t *testing.T) {
+	a := newDocumentHLLSet(nil, 10)
+	b := newDocumentHLLSet(nil, 11)
+
+	if err := a.Merge(b); err == nil {
+		t.Fatal("expected an error when merging sets with different precisions")
+	}
+}
+
+func TestDocumentHLLSetEmpty(t *testing.T) {
+	s := newDocumentHLLSet(nil, 10)
+	if got := s.Count(); got != 0 {
+		t.Fatalf("expected 0 on an empty set, got %d", got)
+	}
+}
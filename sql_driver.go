@@ -0,0 +1,84 @@
+package genji
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"runtime"
+)
+
+func init() {
+	sql.Register("genji", sqlDriver{})
+}
+
+var (
+	_ driver.Driver        = (*sqlDriver)(nil)
+	_ driver.DriverContext = (*sqlDriver)(nil)
+	_ driver.Connector     = (*connector)(nil)
+)
+
+// sqlDriver is the database/sql driver registered under the name "genji".
+type sqlDriver struct{}
+
+// Open opens a new connection, re-opening and re-parsing name on every call.
+// database/sql prefers OpenConnector when available, which only opens name
+// once and lets connections be pooled against the same *DB.
+func (d sqlDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.OpenConnector(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Connect(context.Background())
+}
+
+// OpenConnector opens the database described by name once and returns a
+// driver.Connector that hands out connections wrapping that same *DB,
+// instead of re-opening it on every connection database/sql requests.
+func (d sqlDriver) OpenConnector(name string) (driver.Connector, error) {
+	db, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConnector(db, d), nil
+}
+
+// connector implements driver.Connector on top of an already-open *DB.
+type connector struct {
+	db     *DB
+	driver driver.Driver
+}
+
+func newConnector(db *DB, d driver.Driver) *connector {
+	c := &connector{db: db, driver: d}
+
+	// Close the underlying *DB once the connector is garbage collected.
+	// connector has no Close method, so closing a *sql.DB built from it
+	// does not close the *DB.
+	runtime.SetFinalizer(c, func(c *connector) {
+		_ = c.db.Close()
+	})
+
+	return c
+}
+
+// Connect returns a connection wrapping the connector's shared *DB.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return newConn(c.db), nil
+}
+
+// Driver returns the underlying driver.Driver.
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// OpenConnector returns a driver.Connector wrapping an already-open *DB, so
+// that an application holding one can hand it to sql.OpenDB without Genji
+// opening or parsing a DSN a second time.
+//
+//	db, err := genji.Open("my.db")
+//	sqlDB := sql.OpenDB(genji.OpenConnector(db))
+func OpenConnector(db *DB) driver.Connector {
+	return newConnector(db, sqlDriver{})
+}